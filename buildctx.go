@@ -0,0 +1,90 @@
+package depth
+
+import "go/build"
+
+// buildContext returns a build.Context seeded from build.Default with the
+// Tree's BuildTags, GOOS, GOARCH and CGoEnabled overrides applied, so Resolve
+// can produce dependency trees for platforms other than the host's.
+func (t *Tree) buildContext() build.Context {
+	ctx := build.Default
+
+	if len(t.BuildTags) > 0 {
+		ctx.BuildTags = t.BuildTags
+	}
+	if len(t.GOOS) > 0 {
+		ctx.GOOS = t.GOOS
+	}
+	if len(t.GOARCH) > 0 {
+		ctx.GOARCH = t.GOARCH
+	}
+	if t.CGoEnabled != nil {
+		ctx.CgoEnabled = *t.CGoEnabled
+	}
+
+	return ctx
+}
+
+// allTagsImporter wraps a build.Context and, on Import, aggregates the union
+// of imports reported across every combination of the context's build tags.
+// GOOS, GOARCH and CgoEnabled are taken from the base context and are the
+// same for every combination.
+type allTagsImporter struct {
+	base build.Context
+}
+
+func (a *allTagsImporter) Import(name, srcDir string, mode build.ImportMode) (*build.Package, error) {
+	seen := make(map[string]struct{})
+	var merged *build.Package
+
+	for _, tags := range a.tagCombinations() {
+		ctx := a.base
+		ctx.BuildTags = tags
+
+		pkg, err := ctx.Import(name, srcDir, mode)
+		if err != nil {
+			continue
+		}
+
+		if merged == nil {
+			merged = pkg
+			for _, imp := range pkg.Imports {
+				seen[imp] = struct{}{}
+			}
+			continue
+		}
+
+		for _, imp := range pkg.Imports {
+			if _, ok := seen[imp]; ok {
+				continue
+			}
+			seen[imp] = struct{}{}
+			merged.Imports = append(merged.Imports, imp)
+		}
+	}
+
+	if merged == nil {
+		// None of the tag combinations matched; fall back to the
+		// unmodified context so the caller still gets its error.
+		return a.base.Import(name, srcDir, mode)
+	}
+
+	return merged, nil
+}
+
+// tagCombinations enumerates the power set of the importer's configured
+// build tags, so every constraint combination a source file might use is
+// covered.
+func (a *allTagsImporter) tagCombinations() [][]string {
+	combos := [][]string{nil}
+
+	for _, tag := range a.base.BuildTags {
+		for _, c := range combos[:len(combos)] {
+			next := make([]string, len(c), len(c)+1)
+			copy(next, c)
+			next = append(next, tag)
+			combos = append(combos, next)
+		}
+	}
+
+	return combos
+}
@@ -0,0 +1,37 @@
+package depth
+
+import (
+	"go/build"
+	"testing"
+)
+
+func TestBuildContextAppliesOverrides(t *testing.T) {
+	enabled := true
+	tr := &Tree{
+		BuildTags:  []string{"integration"},
+		GOOS:       "linux",
+		GOARCH:     "arm64",
+		CGoEnabled: &enabled,
+	}
+
+	ctx := tr.buildContext()
+
+	if ctx.GOOS != "linux" || ctx.GOARCH != "arm64" {
+		t.Fatalf("buildContext() GOOS/GOARCH = %s/%s, want linux/arm64", ctx.GOOS, ctx.GOARCH)
+	}
+	if !ctx.CgoEnabled {
+		t.Fatal("buildContext() CgoEnabled = false, want true")
+	}
+	if len(ctx.BuildTags) != 1 || ctx.BuildTags[0] != "integration" {
+		t.Fatalf("buildContext() BuildTags = %v, want [integration]", ctx.BuildTags)
+	}
+}
+
+func TestTagCombinationsIsPowerSet(t *testing.T) {
+	a := &allTagsImporter{base: build.Context{BuildTags: []string{"a", "b"}}}
+
+	combos := a.tagCombinations()
+	if len(combos) != 4 {
+		t.Fatalf("tagCombinations() returned %d combos, want 4 (power set of 2 tags)", len(combos))
+	}
+}
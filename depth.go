@@ -31,6 +31,7 @@ import (
 	"go/build"
 	"os"
 	"regexp"
+	"sync"
 )
 
 // ErrRootPkgNotResolved is returned when the root Pkg of the Tree cannot be resolved,
@@ -52,13 +53,51 @@ type Tree struct {
 	MaxDepth        int
 	MapLevel        int    // 扁平化展示层级
 	ShowPkg         string // 展示哪个包的依赖
-	showFiltered    func(pkgName string) bool
+
+	// ModuleMode enables go.mod-aware resolution via ModuleImporter instead
+	// of the GOPATH/vendor semantics build.Default follows. It has no effect
+	// if Importer is set explicitly.
+	ModuleMode bool
+
+	// Concurrency bounds how many of one package's imports are resolved in
+	// parallel. If zero, runtime.NumCPU is used. Each package resolves its
+	// imports with its own pool, so the number of resolutions in flight
+	// across the whole tree is not bounded by it.
+	Concurrency int
+
+	// BuildTags, GOOS, GOARCH and CGoEnabled override the corresponding
+	// fields of build.Default for this Tree's resolution, so cross-platform
+	// dependency trees can be produced. They have no effect if Importer is
+	// set explicitly or ModuleMode is set.
+	BuildTags  []string
+	GOOS       string
+	GOARCH     string
+	CGoEnabled *bool
+
+	// AllBuildTags resolves the union of imports across every subset of
+	// BuildTags, instead of only the full set. GOOS, GOARCH and CGoEnabled
+	// are not varied.
+	AllBuildTags bool
+
+	showFiltered func(pkgName string) bool
 
 	MatcherReg string
 	matched    *regexp.Regexp
 
+	// Ignored lists import paths to prune from the tree, using the same
+	// exact/prefix/negation syntax as dep/glide manifests. It complements
+	// MatcherReg, which is an include-filter, with an explicit exclude
+	// mechanism. The rules are compiled by Init; Resolve without a prior
+	// Init does not apply them.
+	Ignored []string
+	ignored *IgnoredRuleset
+
 	Importer Importer
 
+	// cacheMu guards importCache and importPkgCache, which are read and
+	// written concurrently once imports are dispatched to the worker pool
+	// (see resolveConcurrently).
+	cacheMu        sync.Mutex
 	importCache    map[string]struct{}
 	importPkgCache map[string]*build.Package
 }
@@ -71,11 +110,20 @@ func (t *Tree) ShowFilter(pkgName string) bool {
 }
 
 func (t *Tree) hasSeenPkg(name string) (*build.Package, bool) {
+	t.cacheMu.Lock()
+	defer t.cacheMu.Unlock()
+
 	pkg, exist := t.importPkgCache[name]
 	return pkg, exist
 }
 
 func (t *Tree) cachePkg(name string, pkg *build.Package) {
+	t.cacheMu.Lock()
+	defer t.cacheMu.Unlock()
+
+	if t.importPkgCache == nil {
+		t.importPkgCache = make(map[string]*build.Package)
+	}
 	t.importPkgCache[name] = pkg
 }
 
@@ -83,6 +131,9 @@ func (t *Tree) Init() {
 	if len(t.MatcherReg) > 0 {
 		t.matched = regexp.MustCompile(t.MatcherReg)
 	}
+	if len(t.Ignored) > 0 {
+		t.ignored = NewIgnoredRuleset(t.Ignored)
+	}
 	if len(t.ShowPkg) > 0 {
 		t.showFiltered = func(pkgName string) bool {
 			if pkgName != t.ShowPkg {
@@ -113,9 +164,19 @@ func (t *Tree) Resolve(name string) error {
 	// reuse the same cache.
 	t.importCache = nil
 
-	// Allow custom importers, but use build.Default if none is provided.
+	// Allow custom importers. If none is provided, use a ModuleImporter when
+	// ModuleMode is set, else an allTagsImporter when AllBuildTags is set,
+	// else build.Default with the Tree's overrides (see buildContext).
 	if t.Importer == nil {
-		t.Importer = &build.Default
+		switch {
+		case t.ModuleMode:
+			t.Importer = &ModuleImporter{}
+		case t.AllBuildTags:
+			t.Importer = &allTagsImporter{base: t.buildContext()}
+		default:
+			ctx := t.buildContext()
+			t.Importer = &ctx
+		}
 	}
 
 	t.Root.Resolve(t.Importer)
@@ -151,6 +212,13 @@ func (t *Tree) shouldFiltered(name string) bool {
 	return false
 }
 
+// shouldIgnore returns true if name matches one of the Tree's Ignored rules,
+// meaning it (and its unique transitive imports) should be pruned from the
+// tree during traversal.
+func (t *Tree) shouldIgnore(name string) bool {
+	return t.ignored.Ignored(name)
+}
+
 // isAtMaxDepth returns true when the depth of the Pkg provided is at or beyond the maximum
 // depth allowed by the tree.
 //
@@ -166,6 +234,9 @@ func (t *Tree) isAtMaxDepth(p *Pkg) bool {
 // hasSeenImport returns true if the import name provided has already been seen within the tree.
 // This function only returns false for a name once.
 func (t *Tree) hasSeenImport(name string) bool {
+	t.cacheMu.Lock()
+	defer t.cacheMu.Unlock()
+
 	if t.importCache == nil {
 		t.importCache = make(map[string]struct{})
 	}
@@ -0,0 +1,98 @@
+package depth
+
+import "sort"
+
+// FlattenOptions customizes the behavior of Tree.Flatten.
+type FlattenOptions struct {
+	// OmitStdLib excludes standard library packages from the result.
+	OmitStdLib bool
+
+	// OmitInternal excludes internal packages (Pkg.Internal) from the
+	// result.
+	OmitInternal bool
+
+	// Root restricts the walk to the subtree rooted at the package with
+	// this import path. If empty, the Tree's Root is used.
+	Root string
+}
+
+// ReachMap maps an import path to the Pkg it resolved to, for every package
+// reachable from a Tree.Flatten walk.
+type ReachMap map[string]*Pkg
+
+// Flatten walks the resolved Tree and returns a sorted, deduplicated list of
+// every package reachable from the root (or from opts.Root, if set),
+// including that root itself.
+func (t *Tree) Flatten(opts FlattenOptions) []string {
+	reach := t.reachMap(opts)
+
+	names := make([]string, 0, len(reach))
+	for name := range reach {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// reachMap performs the walk shared by Flatten, returning the full ReachMap
+// so callers that need the Pkg nodes, not just their import paths, can
+// reuse it instead of walking the tree again.
+func (t *Tree) reachMap(opts FlattenOptions) ReachMap {
+	root := t.Root
+	if len(opts.Root) > 0 {
+		root = t.find(opts.Root)
+	}
+	if root == nil {
+		return ReachMap{}
+	}
+
+	reach := make(ReachMap)
+
+	var walk func(p *Pkg)
+	walk = func(p *Pkg) {
+		if p == nil {
+			return
+		}
+		if opts.OmitStdLib && isStdLib(p.Name) {
+			return
+		}
+		if opts.OmitInternal && p.Internal {
+			return
+		}
+		if _, ok := reach[p.Name]; ok {
+			return
+		}
+		reach[p.Name] = p
+
+		for i := range p.Deps {
+			walk(&p.Deps[i])
+		}
+	}
+	walk(root)
+
+	return reach
+}
+
+// find locates the Pkg with the given import path anywhere in the Tree, or
+// nil if it isn't present.
+func (t *Tree) find(name string) *Pkg {
+	var found *Pkg
+
+	var walk func(p *Pkg)
+	walk = func(p *Pkg) {
+		if found != nil || p == nil {
+			return
+		}
+		if p.Name == name {
+			found = p
+			return
+		}
+		for i := range p.Deps {
+			walk(&p.Deps[i])
+		}
+	}
+	walk(t.Root)
+
+	return found
+}
@@ -0,0 +1,54 @@
+package depth
+
+import "testing"
+
+func sampleTree() *Tree {
+	tr := &Tree{}
+	tr.Root = &Pkg{
+		Name: "root",
+		Tree: tr,
+		Deps: []Pkg{
+			{Name: "strings", Tree: tr, Internal: true},
+			{Name: "github.com/foo/bar", Tree: tr, Deps: []Pkg{
+				{Name: "github.com/foo/baz", Tree: tr},
+			}},
+		},
+	}
+	return tr
+}
+
+func TestFlatten(t *testing.T) {
+	tr := sampleTree()
+
+	got := tr.Flatten(FlattenOptions{})
+	want := []string{"github.com/foo/bar", "github.com/foo/baz", "root", "strings"}
+
+	if len(got) != len(want) {
+		t.Fatalf("Flatten() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Flatten() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestFlattenOmitStdLib(t *testing.T) {
+	tr := sampleTree()
+
+	got := tr.Flatten(FlattenOptions{OmitStdLib: true})
+	for _, name := range got {
+		if name == "strings" {
+			t.Fatalf("Flatten(OmitStdLib: true) = %v, want no stdlib packages", got)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	tr := sampleTree()
+
+	p := tr.find("github.com/foo/baz")
+	if p == nil {
+		t.Fatal("find() = nil, want github.com/foo/baz")
+	}
+}
@@ -0,0 +1,80 @@
+package depth
+
+import "strings"
+
+// IgnoredRuleset compiles a Tree's Ignored patterns into a form that can be
+// checked against import paths. Patterns mirror the subset of dep/glide
+// manifest syntax commonly used to exclude subtrees:
+//
+//   - "github.com/foo/bar" matches that import path exactly.
+//   - "github.com/foo/bar/..." matches that import path and everything
+//     beneath it.
+//   - "!github.com/foo/bar/baz" re-includes a path that would otherwise be
+//     excluded by a broader rule. The "!" may prefix either form above.
+type IgnoredRuleset struct {
+	exact    map[string]struct{}
+	prefixes []string
+
+	negatedExact    map[string]struct{}
+	negatedPrefixes []string
+}
+
+// NewIgnoredRuleset compiles patterns into an IgnoredRuleset.
+func NewIgnoredRuleset(patterns []string) *IgnoredRuleset {
+	r := &IgnoredRuleset{
+		exact:        make(map[string]struct{}),
+		negatedExact: make(map[string]struct{}),
+	}
+
+	for _, p := range patterns {
+		negated := strings.HasPrefix(p, "!")
+		p = strings.TrimPrefix(p, "!")
+
+		if strings.HasSuffix(p, "/...") {
+			prefix := strings.TrimSuffix(p, "/...")
+			if negated {
+				r.negatedPrefixes = append(r.negatedPrefixes, prefix)
+			} else {
+				r.prefixes = append(r.prefixes, prefix)
+			}
+			continue
+		}
+
+		if negated {
+			r.negatedExact[p] = struct{}{}
+		} else {
+			r.exact[p] = struct{}{}
+		}
+	}
+
+	return r
+}
+
+// Ignored returns true if name matches an ignore rule and hasn't been
+// re-included by a negation rule.
+func (r *IgnoredRuleset) Ignored(name string) bool {
+	if r == nil {
+		return false
+	}
+
+	if _, ok := r.negatedExact[name]; ok {
+		return false
+	}
+	if matchesPrefix(name, r.negatedPrefixes) {
+		return false
+	}
+
+	if _, ok := r.exact[name]; ok {
+		return true
+	}
+	return matchesPrefix(name, r.prefixes)
+}
+
+func matchesPrefix(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if name == prefix || strings.HasPrefix(name, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
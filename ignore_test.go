@@ -0,0 +1,34 @@
+package depth
+
+import "testing"
+
+func TestIgnoredRulesetNegatedSubtree(t *testing.T) {
+	r := NewIgnoredRuleset([]string{
+		"k8s.io/api/...",
+		"!k8s.io/api/admission/...",
+	})
+
+	cases := map[string]bool{
+		"k8s.io/api/core/v1":        true,
+		"k8s.io/api/admission/v1":   false,
+		"k8s.io/api/admission/v1/x": false,
+		"k8s.io/client-go/tools":    false,
+	}
+
+	for name, want := range cases {
+		if got := r.Ignored(name); got != want {
+			t.Errorf("Ignored(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestIgnoredRulesetNegatedExact(t *testing.T) {
+	r := NewIgnoredRuleset([]string{
+		"github.com/foo/bar",
+		"!github.com/foo/bar",
+	})
+
+	if r.Ignored("github.com/foo/bar") {
+		t.Fatal("Ignored(\"github.com/foo/bar\") = true, want false after negation")
+	}
+}
@@ -0,0 +1,120 @@
+package depth
+
+import (
+	"go/build"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Module describes the module owning a resolved Pkg: its path, resolved
+// version, and replace-directive target, if any.
+type Module struct {
+	Path    string
+	Version string
+
+	// Replace is set when the module is subject to a replace directive.
+	Replace *Module
+}
+
+// moduleAwareImporter is implemented by importers that can report which
+// Module a previously-imported package belongs to. Pkg.Resolve uses it to
+// populate Pkg.Module directly, so callers don't need to know which
+// Importer is in use.
+type moduleAwareImporter interface {
+	ModuleFor(path string) *Module
+}
+
+// ModuleImporter implements Importer on top of
+// `golang.org/x/tools/go/packages` instead of `go/build`, so Resolve
+// understands go.mod (including replace directives and indirect/pseudo
+// versions) rather than following GOPATH/vendor semantics.
+type ModuleImporter struct {
+	// Dir is the working directory used to locate the enclosing module. If
+	// empty, the working directory passed to Import is used instead.
+	Dir string
+
+	modules map[string]*Module
+}
+
+// ModuleFor returns the Module owning the package at path, or nil if the
+// path hasn't been imported yet or isn't part of a module (e.g. stdlib).
+func (m *ModuleImporter) ModuleFor(path string) *Module {
+	return m.modules[path]
+}
+
+// Import loads name with golang.org/x/tools/go/packages in modules mode and
+// translates the result into a *build.Package, recording module metadata
+// that can later be retrieved with ModuleFor.
+func (m *ModuleImporter) Import(name, srcDir string, mode build.ImportMode) (*build.Package, error) {
+	dir := m.Dir
+	if len(dir) == 0 {
+		dir = srcDir
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports |
+			packages.NeedDeps | packages.NeedModule,
+		Dir: dir,
+	}
+
+	pkgs, err := packages.Load(cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) == 0 {
+		return nil, &build.NoGoError{Dir: dir}
+	}
+
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, pkg.Errors[0]
+	}
+
+	if pkg.Module != nil {
+		if m.modules == nil {
+			m.modules = make(map[string]*Module)
+		}
+		m.modules[pkg.PkgPath] = moduleFromPackages(pkg.Module)
+	}
+
+	bp := &build.Package{
+		Name:       pkg.Name,
+		ImportPath: pkg.PkgPath,
+		Dir:        packageDir(pkg, dir),
+		Goroot:     pkg.Module == nil && isStdLib(pkg.PkgPath),
+	}
+	for imp := range pkg.Imports {
+		bp.Imports = append(bp.Imports, imp)
+	}
+
+	return bp, nil
+}
+
+// packageDir returns pkg's own source directory, derived from its GoFiles,
+// falling back to fallback (the importing package's directory) only when
+// GoFiles is empty (e.g. the package failed to load any files).
+func packageDir(pkg *packages.Package, fallback string) string {
+	if len(pkg.GoFiles) == 0 {
+		return fallback
+	}
+	return filepath.Dir(pkg.GoFiles[0])
+}
+
+func moduleFromPackages(mod *packages.Module) *Module {
+	m := &Module{Path: mod.Path, Version: mod.Version}
+	if mod.Replace != nil {
+		m.Replace = moduleFromPackages(mod.Replace)
+	}
+	return m
+}
+
+// isStdLib reports whether path looks like a standard library import, using
+// the same heuristic as the go tool: the first path segment contains no dot.
+func isStdLib(path string) bool {
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		path = path[:i]
+	}
+	return !strings.Contains(path, ".")
+}
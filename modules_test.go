@@ -0,0 +1,61 @@
+package depth
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestModuleFromPackagesCopiesReplace(t *testing.T) {
+	mod := &packages.Module{
+		Path:    "github.com/foo/bar",
+		Version: "v1.2.3",
+		Replace: &packages.Module{
+			Path:    "github.com/foo/bar-fork",
+			Version: "v0.0.0-20230101000000-abcdef123456",
+		},
+	}
+
+	got := moduleFromPackages(mod)
+
+	if got.Path != mod.Path || got.Version != mod.Version {
+		t.Fatalf("moduleFromPackages() = %+v, want Path/Version %q/%q", got, mod.Path, mod.Version)
+	}
+	if got.Replace == nil || got.Replace.Path != mod.Replace.Path {
+		t.Fatalf("moduleFromPackages() Replace = %+v, want Path %q", got.Replace, mod.Replace.Path)
+	}
+}
+
+func TestPackageDirPrefersGoFiles(t *testing.T) {
+	pkg := &packages.Package{GoFiles: []string{"/repo/foo/bar/file.go"}}
+
+	got := packageDir(pkg, "/caller/dir")
+	want := filepath.Dir("/repo/foo/bar/file.go")
+	if got != want {
+		t.Fatalf("packageDir() = %q, want %q", got, want)
+	}
+}
+
+func TestPackageDirFallsBackWithoutGoFiles(t *testing.T) {
+	pkg := &packages.Package{}
+
+	if got := packageDir(pkg, "/caller/dir"); got != "/caller/dir" {
+		t.Fatalf("packageDir() = %q, want /caller/dir", got)
+	}
+}
+
+func TestIsStdLib(t *testing.T) {
+	cases := map[string]bool{
+		"strings":                  true,
+		"net/http":                 true,
+		"github.com/foo/bar":       false,
+		"golang.org/x/tools/go/ast": false,
+	}
+
+	for path, want := range cases {
+		if got := isStdLib(path); got != want {
+			t.Errorf("isStdLib(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package depth
+
+import "go/build"
+
+// Pkg represents a Go source package, and its dependencies.
+type Pkg struct {
+	Name     string
+	Resolved bool
+
+	Tree *Tree
+
+	Parent *Pkg
+	Deps   []Pkg
+
+	Internal bool
+	Test     bool
+
+	SrcDir string
+
+	Raw *build.Package
+
+	// Module is the module owning this Pkg, populated when Tree.Importer
+	// reports module metadata (see ModuleImporter). It is nil otherwise.
+	Module *Module
+}
+
+// Resolve recursively finds all dependencies for the Pkg name provided, and
+// the packages it depends on.
+func (p *Pkg) Resolve(i Importer) {
+	// If we've already seen this Pkg, reuse the cached build.Package instead
+	// of importing it again.
+	if cur, exists := p.Tree.hasSeenPkg(p.Name); exists {
+		p.copyBuild(i, cur)
+		p.Resolved = true
+		return
+	}
+
+	if len(p.Name) == 0 {
+		p.Resolved = false
+		return
+	}
+
+	pkg, err := i.Import(p.Name, p.SrcDir, 0)
+	if err != nil {
+		p.Resolved = false
+		return
+	}
+
+	p.Tree.cachePkg(p.Name, pkg)
+
+	p.copyBuild(i, pkg)
+	p.Resolved = true
+}
+
+func (p *Pkg) copyBuild(i Importer, pkg *build.Package) {
+	p.adoptCached(i, pkg)
+	p.loadDeps()
+}
+
+// adoptCached populates p from a build.Package already resolved elsewhere in
+// the tree, without re-importing or expanding Deps (the Pkg that resolved it
+// the first time already did that).
+func (p *Pkg) adoptCached(i Importer, pkg *build.Package) {
+	p.SrcDir = pkg.Dir
+	p.Internal = pkg.Goroot
+	p.Raw = pkg
+	p.Resolved = true
+
+	if mi, ok := i.(moduleAwareImporter); ok {
+		p.Module = mi.ModuleFor(pkg.ImportPath)
+	}
+}
+
+func (p *Pkg) loadDeps() {
+	imports := p.Raw.Imports
+	if p.Tree.ResolveTest {
+		imports = append(imports, p.Raw.TestImports...)
+		imports = append(imports, p.Raw.XTestImports...)
+	}
+
+	p.Deps = p.resolveImports(imports)
+}
+
+// resolveImports builds the Deps slice for p, skipping self-imports,
+// filtered/ignored imports, and anything beyond the Tree's MaxDepth. Imports not
+// already seen elsewhere in the tree are resolved in parallel, bounded by
+// the Tree's worker pool (see resolveConcurrently).
+func (p *Pkg) resolveImports(imports []string) []Pkg {
+	var deps []Pkg
+
+	// pending maps an import name to every index in deps still needing a
+	// fresh Resolve call, so duplicate import names dispatch once.
+	pending := make(map[string][]int)
+
+	for _, imp := range imports {
+		if imp == p.Name {
+			// Don't import self.
+			continue
+		}
+		if p.Tree.shouldFiltered(imp) {
+			continue
+		}
+		if p.Tree.shouldIgnore(imp) {
+			continue
+		}
+		if p.Tree.isAtMaxDepth(p) {
+			continue
+		}
+
+		deps = append(deps, Pkg{
+			Name:   imp,
+			Parent: p,
+			Tree:   p.Tree,
+			Test:   p.Tree.ResolveTest,
+		})
+
+		switch {
+		case !p.Tree.shouldResolveInternal(p) && isStdLib(imp):
+			// Leave as an unresolved stub: it still appears in Deps, but
+			// its own dependencies aren't expanded further.
+		case len(pending[imp]) > 0:
+			// Duplicate of an import queued earlier in this list: resolve it
+			// in the same dispatch.
+			pending[imp] = append(pending[imp], len(deps)-1)
+		case p.Tree.hasSeenImport(imp):
+			// Already resolved elsewhere in the tree: adopt the cached
+			// build.Package/Module so Resolved reflects that it genuinely
+			// resolved, without re-expanding its Deps.
+			if cached, ok := p.Tree.hasSeenPkg(imp); ok {
+				deps[len(deps)-1].adoptCached(p.Tree.Importer, cached)
+			}
+		default:
+			pending[imp] = append(pending[imp], len(deps)-1)
+		}
+	}
+
+	names := make([]string, 0, len(pending))
+	for name := range pending {
+		names = append(names, name)
+	}
+
+	p.Tree.resolveConcurrently(names, func(name string) {
+		for _, idx := range pending[name] {
+			deps[idx].Resolve(p.Tree.Importer)
+		}
+	})
+
+	return deps
+}
+
+// depth returns the number of ancestors between p and the Tree's Root.
+func (p *Pkg) depth() int {
+	if p.Parent == nil {
+		return 0
+	}
+
+	return 1 + p.Parent.depth()
+}
@@ -0,0 +1,29 @@
+package depth
+
+import (
+	"go/build"
+	"testing"
+)
+
+func TestResolveImportsAdoptsCachedForAlreadySeenImport(t *testing.T) {
+	tr := &Tree{}
+	root := &Pkg{Name: "root", Tree: tr}
+	tr.Root = root
+
+	cached := &build.Package{ImportPath: "shared", Dir: "/cached/shared"}
+	tr.hasSeenImport("shared") // mark as already resolved elsewhere in the tree
+	tr.cachePkg("shared", cached)
+
+	deps := root.resolveImports([]string{"shared"})
+	if len(deps) != 1 {
+		t.Fatalf("resolveImports() = %d deps, want 1", len(deps))
+	}
+
+	got := deps[0]
+	if !got.Resolved {
+		t.Fatal("already-seen dep should be marked Resolved, got false")
+	}
+	if got.Raw != cached {
+		t.Fatalf("already-seen dep Raw = %v, want cached %v", got.Raw, cached)
+	}
+}
@@ -0,0 +1,39 @@
+package depth
+
+import (
+	"runtime"
+	"sync"
+)
+
+// concurrency returns the Tree's configured Concurrency, or runtime.NumCPU
+// if it hasn't been set.
+func (t *Tree) concurrency() int {
+	if t.Concurrency > 0 {
+		return t.Concurrency
+	}
+	return runtime.NumCPU()
+}
+
+// resolveConcurrently runs resolve once for each name in names, bounding the
+// number of goroutines in flight to t.concurrency(). It blocks until every
+// name has been resolved, so it's safe for callers to read shared state
+// populated by resolve once resolveConcurrently returns.
+func (t *Tree) resolveConcurrently(names []string, resolve func(name string)) {
+	sem := make(chan struct{}, t.concurrency())
+
+	var wg sync.WaitGroup
+	wg.Add(len(names))
+
+	for _, name := range names {
+		sem <- struct{}{}
+
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resolve(name)
+		}(name)
+	}
+
+	wg.Wait()
+}
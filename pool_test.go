@@ -0,0 +1,49 @@
+package depth
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+// BenchmarkResolveConcurrency resolves a package with a wide import graph
+// under a few pool sizes, to confirm Concurrency actually parallelizes
+// Pkg.Resolve rather than leaving it serial.
+func BenchmarkResolveConcurrency(b *testing.B) {
+	for _, c := range []int{1, runtime.NumCPU()} {
+		c := c
+		b.Run(fmt.Sprintf("concurrency=%d", c), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var t Tree
+				t.Concurrency = c
+
+				if err := t.Resolve("net/http"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveConcurrentlyRunsEveryName(t *testing.T) {
+	var tr Tree
+	tr.Concurrency = 2
+
+	names := []string{"a", "b", "c", "d"}
+	seen := make(chan string, len(names))
+
+	tr.resolveConcurrently(names, func(name string) {
+		seen <- name
+	})
+	close(seen)
+
+	got := make(map[string]bool)
+	for name := range seen {
+		got[name] = true
+	}
+	for _, name := range names {
+		if !got[name] {
+			t.Errorf("resolveConcurrently never ran resolve for %q", name)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package depth
+
+import "strings"
+
+// ImportRestriction declares an architectural layering rule: packages under
+// BaseImportPath may only import paths matching one of AllowedPrefixes,
+// except for subtrees listed in IgnoredSubtrees. Standard library imports are
+// always allowed.
+type ImportRestriction struct {
+	BaseImportPath  string
+	AllowedPrefixes []string
+	IgnoredSubtrees []string
+}
+
+// Violation records a single transitive import that breaks an
+// ImportRestriction.
+type Violation struct {
+	Rule       ImportRestriction
+	Importer   string
+	Disallowed string
+}
+
+// Verify walks the resolved Tree and reports every transitive import under
+// each rule's BaseImportPath that doesn't match one of its AllowedPrefixes,
+// skipping standard library imports and the rule's IgnoredSubtrees.
+func (t *Tree) Verify(rules []ImportRestriction) []Violation {
+	var violations []Violation
+
+	for _, rule := range rules {
+		bases := t.findSubtree(rule.BaseImportPath)
+		if len(bases) == 0 {
+			continue
+		}
+
+		ignored := NewIgnoredRuleset(rule.IgnoredSubtrees)
+		seen := make(map[*Pkg]struct{})
+
+		var walk func(p *Pkg)
+		walk = func(p *Pkg) {
+			if p == nil {
+				return
+			}
+			if _, ok := seen[p]; ok {
+				return
+			}
+			seen[p] = struct{}{}
+
+			for i := range p.Deps {
+				dep := &p.Deps[i]
+
+				if isStdLib(dep.Name) || ignored.Ignored(dep.Name) {
+					// The whole subtree below an ignored (or stdlib)
+					// package is exempt, so don't walk into it either.
+					continue
+				}
+
+				if !matchesPrefix(dep.Name, rule.AllowedPrefixes) {
+					violations = append(violations, Violation{
+						Rule:       rule,
+						Importer:   p.Name,
+						Disallowed: dep.Name,
+					})
+				}
+
+				walk(dep)
+			}
+		}
+
+		for _, base := range bases {
+			walk(base)
+		}
+	}
+
+	return violations
+}
+
+// findSubtree returns every Pkg in the Tree whose import path equals prefix
+// or falls under it (prefix/...), mirroring the BaseImportPath wildcard
+// convention used by Ignored.
+func (t *Tree) findSubtree(prefix string) []*Pkg {
+	prefix = strings.TrimSuffix(prefix, "/...")
+
+	var matches []*Pkg
+	seen := make(map[*Pkg]struct{})
+
+	var walk func(p *Pkg)
+	walk = func(p *Pkg) {
+		if p == nil {
+			return
+		}
+		if _, ok := seen[p]; ok {
+			return
+		}
+		seen[p] = struct{}{}
+
+		if p.Name == prefix || strings.HasPrefix(p.Name, prefix+"/") {
+			matches = append(matches, p)
+		}
+
+		for i := range p.Deps {
+			walk(&p.Deps[i])
+		}
+	}
+	walk(t.Root)
+
+	return matches
+}
@@ -0,0 +1,60 @@
+package depth
+
+import "testing"
+
+func layeredTree() *Tree {
+	tr := &Tree{}
+	tr.Root = &Pkg{
+		Name: "github.com/acme/app",
+		Tree: tr,
+		Deps: []Pkg{
+			{Name: "github.com/acme/app/internal/domain/user", Tree: tr, Deps: []Pkg{
+				{Name: "github.com/acme/app/internal/domain/order", Tree: tr},
+				{Name: "github.com/acme/app/internal/transport/http", Tree: tr, Deps: []Pkg{
+					{Name: "github.com/acme/app/internal/transport/http/middleware", Tree: tr},
+				}},
+			}},
+			{Name: "github.com/acme/app/internal/domain/order", Tree: tr, Deps: []Pkg{
+				{Name: "github.com/acme/app/internal/legacy/shim", Tree: tr, Deps: []Pkg{
+					{Name: "github.com/acme/app/internal/transport/http", Tree: tr},
+				}},
+			}},
+		},
+	}
+	return tr
+}
+
+func TestVerifyFindsViolationInSiblingPackage(t *testing.T) {
+	tr := layeredTree()
+
+	violations := tr.Verify([]ImportRestriction{{
+		BaseImportPath:  "github.com/acme/app/internal/domain/...",
+		AllowedPrefixes: []string{"github.com/acme/app/internal/domain"},
+	}})
+
+	var found bool
+	for _, v := range violations {
+		if v.Importer == "github.com/acme/app/internal/domain/user" && v.Disallowed == "github.com/acme/app/internal/transport/http" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Verify() = %+v, want a violation for internal/domain/user -> internal/transport/http", violations)
+	}
+}
+
+func TestVerifyIgnoredSubtreeIsFullyExempt(t *testing.T) {
+	tr := layeredTree()
+
+	violations := tr.Verify([]ImportRestriction{{
+		BaseImportPath:  "github.com/acme/app/internal/domain/...",
+		AllowedPrefixes: []string{"github.com/acme/app/internal/domain"},
+		IgnoredSubtrees: []string{"github.com/acme/app/internal/legacy/..."},
+	}})
+
+	for _, v := range violations {
+		if v.Importer == "github.com/acme/app/internal/legacy/shim" || v.Disallowed == "github.com/acme/app/internal/legacy/shim" {
+			t.Fatalf("Verify() reported a violation involving an ignored subtree: %+v", v)
+		}
+	}
+}